@@ -0,0 +1,110 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/rest-layer/resource"
+	"github.com/rs/rest-layer/schema/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// buildPipeline sandwiches the caller-supplied pipeline stages between the
+// $match/$sort derived from q and the $skip/$limit derived from q.Window, so
+// a resource's aggregation always honors the same predicate and window as a
+// plain Find. The window is applied after pipeline rather than before it:
+// stages like $group reduce or reorder documents, so windowing first would
+// paginate the pre-aggregation rows instead of the pipeline's actual output
+// (e.g. truncating the input to a $group/$sum rollup instead of paging the
+// computed totals). A leading $search stage in pipeline is the one exception:
+// Atlas Search only accepts $search as a pipeline's first stage, so it is
+// kept ahead of the $match/$sort.
+func buildPipeline(q *query.Query, pipeline mongo.Pipeline) (mongo.Pipeline, error) {
+	qry, err := getQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	p := mongo.Pipeline{}
+	if len(pipeline) > 0 && len(pipeline[0]) > 0 && pipeline[0][0].Key == "$search" {
+		p = append(p, pipeline[0])
+		pipeline = pipeline[1:]
+	}
+	if len(qry) > 0 {
+		p = append(p, bson.D{{Key: "$match", Value: qry}})
+	}
+	if sort := getSort(q); len(sort) > 0 {
+		p = append(p, bson.D{{Key: "$sort", Value: sort}})
+	}
+	p = append(p, pipeline...)
+	if q.Window != nil {
+		if q.Window.Offset > 0 {
+			p = append(p, bson.D{{Key: "$skip", Value: int64(q.Window.Offset)}})
+		}
+		if q.Window.Limit > -1 {
+			p = append(p, bson.D{{Key: "$limit", Value: int64(q.Window.Limit)}})
+		}
+	}
+	return p, nil
+}
+
+// Aggregate runs pipeline against the collection, preceded by the
+// $match/$sort stages derived from q (after a leading $search stage, if
+// pipeline has one) and followed by the $skip/$limit
+// stages derived from q.Window (see buildPipeline for why the window is
+// applied last), and decodes the resulting documents into resource.Items
+// using the standard _id/_etag/_updated handling. This lets a resource
+// declare stages such as $lookup, $group or $addFields to compute joined or
+// derived fields that a plain Find cannot produce.
+func (m Handler) Aggregate(ctx context.Context, q *query.Query, pipeline mongo.Pipeline) (*resource.ItemList, error) {
+	p, err := buildPipeline(q, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := m.c(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.close(c)
+
+	aggregateOptions := options.Aggregate()
+	if dl, ok := ctx.Deadline(); ok {
+		aggregateOptions.SetMaxTime(time.Until(dl))
+	}
+
+	cursor, err := c.Aggregate(ctx, p, aggregateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &resource.ItemList{
+		Total: -1,
+		Limit: -1,
+		Items: []*resource.Item{},
+	}
+	if q.Window != nil {
+		list.Limit = q.Window.Limit
+	}
+
+	for cursor.Next(ctx) {
+		var mItem mongoItem
+		if err := cursor.Decode(&mItem); err != nil {
+			return nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			cursor.Close(ctx)
+			return nil, err
+		}
+		list.Items = append(list.Items, newItem(&mItem))
+	}
+	if err := cursor.Close(ctx); err != nil {
+		return nil, err
+	}
+	if list.Limit < 0 || len(list.Items) < list.Limit {
+		list.Total = len(list.Items)
+	}
+	return list, nil
+}
@@ -0,0 +1,291 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/rs/rest-layer/resource"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultBulkBatchSize caps how many write models are sent in a single
+// mongo.BulkWrite call when a Handler wasn't configured via
+// WithBulkBatchSize.
+const defaultBulkBatchSize = 1000
+
+// WithBulkBatchSize overrides how many operations InsertMany, UpdateMany and
+// DeleteMany (and the single-item Insert) group into each mongo.BulkWrite
+// request.
+func WithBulkBatchSize(n int) HandlerOption {
+	return func(h *Handler) {
+		h.bulkBatchSize = n
+	}
+}
+
+// BulkResult reports the outcome of a bulk write, keyed by each item's index
+// in the slice passed to InsertMany/UpdateMany/DeleteMany, so callers
+// can tell which items succeeded and why the others didn't without the
+// whole batch failing.
+type BulkResult struct {
+	// Errors maps an input index to the error for that item. An index
+	// absent from Errors succeeded. Duplicate key errors are reported as
+	// resource.ErrConflict, matching Insert's single-item behavior.
+	Errors map[int]error
+	// InsertedCount, MatchedCount, ModifiedCount and DeletedCount total the
+	// documents affected across every batch sent.
+	InsertedCount int
+	MatchedCount  int
+	ModifiedCount int
+	DeletedCount  int
+}
+
+// bulkWrite sends models to the collection in batches of at most the
+// Handler's configured batch size, with ordered execution disabled so one
+// failing operation doesn't block the rest of the batch. The returned error
+// is only set for failures that aren't attributable to a specific item
+// (e.g. a context deadline or a connection error); per-item failures are
+// reported through the returned BulkResult.
+func (m Handler) bulkWrite(ctx context.Context, models []mongo.WriteModel) (*BulkResult, error) {
+	c, err := m.c(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.close(c)
+
+	batchSize := m.bulkBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBulkBatchSize
+	}
+
+	res := &BulkResult{Errors: map[int]error{}}
+	bulkOptions := options.BulkWrite().SetOrdered(false)
+	for start := 0; start < len(models); start += batchSize {
+		end := start + batchSize
+		if end > len(models) {
+			end = len(models)
+		}
+
+		info, err := c.BulkWrite(ctx, models[start:end], bulkOptions)
+		if info != nil {
+			res.InsertedCount += int(info.InsertedCount)
+			res.MatchedCount += int(info.MatchedCount)
+			res.ModifiedCount += int(info.ModifiedCount)
+			res.DeletedCount += int(info.DeletedCount)
+		}
+		if err != nil {
+			var bwe mongo.BulkWriteException
+			if !errors.As(err, &bwe) {
+				return res, err
+			}
+			for _, we := range bwe.WriteErrors {
+				idx := start + we.Index
+				if we.Code == 11000 {
+					res.Errors[idx] = resource.ErrConflict
+				} else {
+					res.Errors[idx] = we
+				}
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return res, ctx.Err()
+	}
+	return res, nil
+}
+
+// InsertMany inserts items via mongo.BulkWrite instead of a single
+// InsertMany round trip per Insert call, batching large imports and
+// reporting which items hit a duplicate key through the returned
+// BulkResult rather than failing the whole batch.
+func (m Handler) InsertMany(ctx context.Context, items []*resource.Item) (*BulkResult, error) {
+	models := make([]mongo.WriteModel, len(items))
+	for i, item := range items {
+		models[i] = mongo.NewInsertOneModel().SetDocument(newMongoItem(item))
+	}
+	return m.bulkWrite(ctx, models)
+}
+
+// UpdateMany replaces each item in items with its counterpart in originals,
+// honoring the same etag-based optimistic concurrency as Update. Since
+// mongo.BulkWrite doesn't report per-operation matched counts, a replacement
+// that matched nothing (not found, or etag mismatch) isn't surfaced as a
+// mongo.BulkWriteException; reportUnmatched re-checks those items with a
+// single $in query so BulkResult.Errors still distinguishes them instead of
+// reporting them as successes.
+func (m Handler) UpdateMany(ctx context.Context, items, originals []*resource.Item) (*BulkResult, error) {
+	models := make([]mongo.WriteModel, len(items))
+	for i, item := range items {
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(etagFilter(originals[i])).
+			SetReplacement(newMongoItem(item))
+	}
+	res, err := m.bulkWrite(ctx, models)
+	if err != nil {
+		return res, err
+	}
+	if err := m.reportUnmatched(ctx, items, originals, res); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// reportUnmatched fills in res.Errors for every UpdateMany item that the
+// bulk write didn't already flag but that didn't actually match a document,
+// by comparing the current _etag of each such item's _id (fetched with a
+// single $in query) against the *new* etag in items[i]: a successful
+// replace leaves that new etag stored, so matching it means the item
+// actually succeeded; absent means resource.ErrNotFound; present with any
+// other etag means resource.ErrConflict.
+func (m Handler) reportUnmatched(ctx context.Context, items, originals []*resource.Item, res *BulkResult) error {
+	unerrored := make([]int, 0, len(originals))
+	for i := range originals {
+		if _, failed := res.Errors[i]; !failed {
+			unerrored = append(unerrored, i)
+		}
+	}
+	if res.MatchedCount >= len(unerrored) {
+		// Every unerrored replacement matched a document; nothing left to
+		// disambiguate.
+		return nil
+	}
+
+	ids := make([]interface{}, len(unerrored))
+	for j, i := range unerrored {
+		ids[j] = originals[i].ID
+	}
+	c, err := m.c(ctx)
+	if err != nil {
+		return err
+	}
+	defer m.close(c)
+
+	cursor, err := c.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, options.Find().SetProjection(bson.M{"_etag": 1}))
+	if err != nil {
+		return err
+	}
+	etags := map[interface{}]string{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID   interface{} `bson:"_id"`
+			ETag string      `bson:"_etag"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			cursor.Close(ctx)
+			return err
+		}
+		etags[doc.ID] = doc.ETag
+	}
+	if err := cursor.Close(ctx); err != nil {
+		return err
+	}
+
+	applyUnmatched(items, originals, unerrored, etags, res)
+	return ctx.Err()
+}
+
+// applyUnmatched fills res.Errors, for every index in unerrored, from etags
+// (the current _etag per original _id, as fetched by reportUnmatched): an
+// id missing from etags means resource.ErrNotFound; present but not equal
+// to the new etag in items[i] means resource.ErrConflict; equal means the
+// replace actually succeeded, so res.Errors is left untouched for it.
+func applyUnmatched(items, originals []*resource.Item, unerrored []int, etags map[interface{}]string, res *BulkResult) {
+	for _, i := range unerrored {
+		etag, found := etags[originals[i].ID]
+		switch {
+		case !found:
+			res.Errors[i] = resource.ErrNotFound
+		case etag != items[i].ETag:
+			res.Errors[i] = resource.ErrConflict
+		}
+	}
+}
+
+// DeleteMany removes each item in items, honoring the same etag-based
+// optimistic concurrency as Delete. Unlike a replace, a successful delete
+// leaves no trace in the collection to distinguish it from "never existed",
+// so the current _id/_etag of every item is snapshotted with a single $in
+// query before the bulk write runs; afterwards that snapshot is all that's
+// needed to tell a not-found item from an etag-mismatched one from an
+// actual success.
+func (m Handler) DeleteMany(ctx context.Context, items []*resource.Item) (*BulkResult, error) {
+	existing, err := m.existingEtags(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]mongo.WriteModel, len(items))
+	for i, item := range items {
+		models[i] = mongo.NewDeleteOneModel().SetFilter(etagFilter(item))
+	}
+	res, err := m.bulkWrite(ctx, models)
+	if err != nil {
+		return res, err
+	}
+
+	for i, item := range items {
+		if _, failed := res.Errors[i]; failed {
+			continue
+		}
+		etag, found := existing[item.ID]
+		switch {
+		case !found:
+			res.Errors[i] = resource.ErrNotFound
+		case etag != item.ETag && !(etag == "" && strings.HasPrefix(item.ETag, "p-")):
+			// A "p-" etag stands for a document stored without _etag, as
+			// in etagFilter.
+			res.Errors[i] = resource.ErrConflict
+		}
+	}
+	return res, nil
+}
+
+// existingEtags fetches the current _etag of every item's _id with a single
+// $in query, used by DeleteMany to classify deletes the bulk write itself
+// can't attribute to a specific item.
+func (m Handler) existingEtags(ctx context.Context, items []*resource.Item) (map[interface{}]string, error) {
+	ids := make([]interface{}, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	c, err := m.c(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.close(c)
+
+	cursor, err := c.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, options.Find().SetProjection(bson.M{"_etag": 1}))
+	if err != nil {
+		return nil, err
+	}
+	etags := map[interface{}]string{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID   interface{} `bson:"_id"`
+			ETag string      `bson:"_etag"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			cursor.Close(ctx)
+			return nil, err
+		}
+		etags[doc.ID] = doc.ETag
+	}
+	if err := cursor.Close(ctx); err != nil {
+		return nil, err
+	}
+	return etags, ctx.Err()
+}
+
+// etagFilter builds the same "_id"/"_etag" selector used by Update and
+// Delete for optimistic concurrency.
+func etagFilter(item *resource.Item) bson.M {
+	s := bson.M{"_id": item.ID}
+	if strings.HasPrefix(item.ETag, "p-") {
+		s["_etag"] = bson.M{"$exists": false}
+	} else {
+		s["_etag"] = item.ETag
+	}
+	return s
+}
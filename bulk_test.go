@@ -0,0 +1,56 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/rs/rest-layer/resource"
+)
+
+func TestApplyUnmatched(t *testing.T) {
+	originals := []*resource.Item{
+		{ID: "a", ETag: "etag-a-old"},
+		{ID: "b", ETag: "etag-b-old"},
+		{ID: "c", ETag: "etag-c-old"},
+	}
+	items := []*resource.Item{
+		{ID: "a", ETag: "etag-a-new"},
+		{ID: "b", ETag: "etag-b-new"},
+		{ID: "c", ETag: "etag-c-new"},
+	}
+	// "a" and "c" were actually replaced (the store now holds their new
+	// etag); "b" lost a race to a concurrent write, which left an etag
+	// other than its original one, so its replace never matched.
+	etags := map[interface{}]string{
+		"a": "etag-a-new",
+		"b": "etag-b-concurrent",
+		"c": "etag-c-new",
+	}
+	res := &BulkResult{Errors: map[int]error{}}
+
+	applyUnmatched(items, originals, []int{0, 1, 2}, etags, res)
+
+	if len(res.Errors) != 1 {
+		t.Fatalf("expected exactly one error, got %v", res.Errors)
+	}
+	if err := res.Errors[1]; err != resource.ErrConflict {
+		t.Errorf("item 1 (etag mismatch): got %v, want resource.ErrConflict", err)
+	}
+	if err, ok := res.Errors[0]; ok {
+		t.Errorf("item 0 (successful replace): got %v, want no error", err)
+	}
+	if err, ok := res.Errors[2]; ok {
+		t.Errorf("item 2 (successful replace): got %v, want no error", err)
+	}
+}
+
+func TestApplyUnmatchedNotFound(t *testing.T) {
+	originals := []*resource.Item{{ID: "missing", ETag: "etag-old"}}
+	items := []*resource.Item{{ID: "missing", ETag: "etag-new"}}
+	res := &BulkResult{Errors: map[int]error{}}
+
+	applyUnmatched(items, originals, []int{0}, map[interface{}]string{}, res)
+
+	if err := res.Errors[0]; err != resource.ErrNotFound {
+		t.Errorf("got %v, want resource.ErrNotFound", err)
+	}
+}
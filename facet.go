@@ -0,0 +1,102 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/rest-layer/resource"
+	"github.com/rs/rest-layer/schema/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WithExactTotals makes Find issue a $facet aggregation that returns an
+// exact document count alongside the page of results, instead of the
+// default behavior of deducing (or omitting) the total. This costs an extra
+// COLLSCAN when no index supports the query, but many admin/list UIs need
+// the exact total that the deduced value can't always provide.
+func WithExactTotals(enabled bool) HandlerOption {
+	return func(h *Handler) {
+		h.exactTotals = enabled
+	}
+}
+
+// facetResult mirrors the shape of the $facet aggregation findWithExactTotals
+// runs: one branch with the page of documents, one with a single $count doc.
+type facetResult struct {
+	Items []mongoItem `bson:"items"`
+	Total []struct {
+		Count int `bson:"count"`
+	} `bson:"total"`
+}
+
+// findWithExactTotals runs the same predicate/sort/projection/window as
+// Find, but as a single aggregation with a $facet stage returning both the
+// page of documents and a $count, so the exact total doesn't require a
+// separate Count round trip.
+func (m Handler) findWithExactTotals(ctx context.Context, q *query.Query) (*resource.ItemList, error) {
+	qry, err := getQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	itemsPipeline := mongo.Pipeline{}
+	if sort := getSort(q); len(sort) > 0 {
+		itemsPipeline = append(itemsPipeline, bson.D{{Key: "$sort", Value: sort}})
+	}
+	limit := -1
+	if q.Window != nil {
+		limit = q.Window.Limit
+		if q.Window.Offset > 0 {
+			itemsPipeline = append(itemsPipeline, bson.D{{Key: "$skip", Value: int64(q.Window.Offset)}})
+		}
+		if q.Window.Limit > -1 {
+			itemsPipeline = append(itemsPipeline, bson.D{{Key: "$limit", Value: int64(q.Window.Limit)}})
+		}
+	}
+	if proj := getProjection(q); proj != nil {
+		itemsPipeline = append(itemsPipeline, bson.D{{Key: "$project", Value: proj}})
+	}
+
+	pipeline := mongo.Pipeline{}
+	if len(qry) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: qry}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"items": itemsPipeline,
+		"total": mongo.Pipeline{bson.D{{Key: "$count", Value: "count"}}},
+	}}})
+
+	c, err := m.c(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.close(c)
+
+	aggregateOptions := options.Aggregate()
+	if dl, ok := ctx.Deadline(); ok {
+		aggregateOptions.SetMaxTime(time.Until(dl))
+	}
+
+	cursor, err := c.Aggregate(ctx, pipeline, aggregateOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	list := &resource.ItemList{Limit: limit, Items: []*resource.Item{}}
+	if cursor.Next(ctx) {
+		var res facetResult
+		if err := cursor.Decode(&res); err != nil {
+			return nil, err
+		}
+		for i := range res.Items {
+			list.Items = append(list.Items, newItem(&res.Items[i]))
+		}
+		if len(res.Total) > 0 {
+			list.Total = res.Total[0].Count
+		}
+	}
+	return list, cursor.Err()
+}
@@ -0,0 +1,108 @@
+package mongo
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/rs/rest-layer/resource"
+	"github.com/rs/rest-layer/schema"
+	"github.com/rs/rest-layer/schema/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// FullText is a query.Expression matching a free-text search, translated to
+// MongoDB's $text operator wherever it appears in a query predicate;
+// Handler.AggregateFullText (enabled by WithAtlasSearch) runs it as an
+// Atlas Search $search stage instead. Resources expose it by
+// parsing a filter such as `search=="foo bar"` into a FullText expression
+// before handing the predicate to this package.
+type FullText struct {
+	// Value is the search phrase, as passed to $text's $search (or, under
+	// Atlas Search, the compound query text).
+	Value string
+	// Negated excludes documents matching Value instead of requiring them.
+	Negated bool
+	// Language selects the $text stemming/stop-word language. Empty means
+	// the collection's default language.
+	Language string
+}
+
+// Match is required to satisfy query.Expression, but full-text matching has
+// no meaning against an in-memory payload: it only translates through this
+// package's query builders.
+func (f *FullText) Match(payload map[string]interface{}) bool {
+	return false
+}
+
+// Prepare implements query.Expression. FullText matches against the whole
+// document rather than a schema field, so there is nothing to validate.
+func (f *FullText) Prepare(validator schema.Validator) error {
+	return nil
+}
+
+// String implements query.Expression.
+func (f *FullText) String() string {
+	op := "$text"
+	if f.Negated {
+		op = "$not" + op
+	}
+	return op + ": " + strconv.Quote(f.Value)
+}
+
+// textSearchStage translates f into a $text match clause.
+func textSearchStage(f *FullText) bson.M {
+	search := bson.M{"$search": f.Value}
+	if f.Language != "" {
+		search["$language"] = f.Language
+	}
+	if f.Negated {
+		search["$search"] = "-" + f.Value
+	}
+	return bson.M{"$text": search}
+}
+
+// textScoreProjection returns a projection of the `$text` relevance score
+// so it can be referenced from a sort as `{score: {$meta: "textScore"}}`.
+func textScoreProjection() bson.M {
+	return bson.M{"score": bson.M{"$meta": "textScore"}}
+}
+
+// atlasSearchStage builds an Atlas Search aggregation stage for f, using a
+// compound query so fuzzy matching and must/should clauses can be layered on
+// top of the plain phrase match.
+func atlasSearchStage(index string, f *FullText) bson.D {
+	clause := bson.M{
+		"text": bson.M{
+			"query": f.Value,
+			"path":  bson.M{"wildcard": "*"},
+			"fuzzy": bson.M{},
+		},
+	}
+	compound := bson.M{}
+	if f.Negated {
+		compound["mustNot"] = []bson.M{clause}
+	} else {
+		compound["must"] = []bson.M{clause}
+	}
+	stage := bson.M{"compound": compound}
+	if index != "" {
+		stage["index"] = index
+	}
+	return bson.D{{Key: "$search", Value: stage}}
+}
+
+// AggregateFullText runs a full-text search against the Atlas Search index
+// named index, applying f's query (optionally negated) before the rest of
+// pipeline and q's own predicate/sort/window. It requires the Handler to
+// have been built with WithAtlasSearch; otherwise it returns
+// resource.ErrNotImplemented so callers fall back to the $text path via a
+// plain Find/FullText predicate.
+func (m Handler) AggregateFullText(ctx context.Context, index string, f *FullText, q *query.Query, pipeline mongo.Pipeline) (*resource.ItemList, error) {
+	if !m.atlasSearch {
+		return nil, resource.ErrNotImplemented
+	}
+	p := mongo.Pipeline{atlasSearchStage(index, f)}
+	p = append(p, pipeline...)
+	return m.Aggregate(ctx, q, p)
+}
@@ -0,0 +1,224 @@
+package mongo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rs/rest-layer/resource"
+	"github.com/rs/rest-layer/schema/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// gridFSContentField is the payload field whose value is stored in GridFS
+// instead of being inlined in the files collection document.
+const gridFSContentField = "content"
+
+// gridFSHandler is a resource.Storer backed by a GridFS bucket. Metadata
+// (_etag/_updated and any other payload fields) lives in the bucket's files
+// collection; the content field is streamed to/from the bucket's chunks
+// collection, so payloads are not subject to the 16 MiB BSON document cap
+// that the plain Handler implicitly imposes.
+type gridFSHandler struct {
+	bucket *gridfs.Bucket
+	files  *mongo.Collection
+}
+
+// NewGridFSHandler returns a resource.Storer that stores a resource's
+// "content" field via a GridFS bucket in db, using the given bucket name for
+// its files/chunks collections (e.g. "fs" yields "fs.files"/"fs.chunks").
+func NewGridFSHandler(client *mongo.Client, db, bucket string) (resource.Storer, error) {
+	d := client.Database(db)
+	b, err := gridfs.NewBucket(d, options.GridFSBucket().SetName(bucket))
+	if err != nil {
+		return nil, err
+	}
+	return &gridFSHandler{
+		bucket: b,
+		files:  d.Collection(bucket + ".files"),
+	}, nil
+}
+
+func newGridFSMetadata(i *resource.Item) bson.M {
+	meta := bson.M{
+		"etag":    i.ETag,
+		"updated": i.Updated,
+	}
+	for k, v := range i.Payload {
+		if k == "id" || k == gridFSContentField {
+			continue
+		}
+		meta[k] = v
+	}
+	return meta
+}
+
+func newItemFromGridFSFile(id interface{}, etag string, updated time.Time, metadata bson.M) *resource.Item {
+	payload := map[string]interface{}{"id": id}
+	for k, v := range metadata {
+		if k == "etag" || k == "updated" {
+			continue
+		}
+		payload[k] = v
+	}
+	return &resource.Item{ID: id, ETag: etag, Updated: updated, Payload: payload}
+}
+
+// Insert uploads each item's "content" field (an io.Reader) to the bucket,
+// storing the rest of the payload as file metadata.
+func (h *gridFSHandler) Insert(ctx context.Context, items []*resource.Item) error {
+	for _, item := range items {
+		r, ok := item.Payload[gridFSContentField].(io.Reader)
+		if !ok {
+			r = bytes.NewReader(nil)
+		}
+		id := item.ID
+		if id == nil {
+			id = primitive.NewObjectID()
+		}
+		uploadOpts := options.GridFSUpload().SetMetadata(newGridFSMetadata(item))
+		if err := h.bucket.UploadFromStreamWithID(id, filename(item), r, uploadOpts); err != nil {
+			if isDup(err) {
+				return resource.ErrConflict
+			}
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// Update replaces an existing file's content and metadata, enforcing the
+// same etag-based optimistic concurrency as Handler.Update.
+func (h *gridFSHandler) Update(ctx context.Context, item *resource.Item, original *resource.Item) error {
+	s := bson.M{"_id": original.ID, "metadata.etag": original.ETag}
+	n, err := h.files.CountDocuments(ctx, s)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		if err := h.files.FindOne(ctx, bson.M{"_id": original.ID}).Err(); err == mongo.ErrNoDocuments {
+			return resource.ErrNotFound
+		}
+		return resource.ErrConflict
+	}
+	if err := h.bucket.DeleteContext(ctx, original.ID); err != nil {
+		return err
+	}
+	r, ok := item.Payload[gridFSContentField].(io.Reader)
+	if !ok {
+		r = bytes.NewReader(nil)
+	}
+	uploadOpts := options.GridFSUpload().SetMetadata(newGridFSMetadata(item))
+	return h.bucket.UploadFromStreamWithID(original.ID, filename(item), r, uploadOpts)
+}
+
+// Delete removes a file and its chunks from the bucket.
+func (h *gridFSHandler) Delete(ctx context.Context, item *resource.Item) error {
+	if err := h.bucket.DeleteContext(ctx, item.ID); err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return resource.ErrNotFound
+		}
+		return err
+	}
+	return ctx.Err()
+}
+
+// Clear removes every file matching q from the bucket.
+func (h *gridFSHandler) Clear(ctx context.Context, q *query.Query) (int, error) {
+	qry, err := getQuery(q)
+	if err != nil {
+		return 0, err
+	}
+	cursor, err := h.files.Find(ctx, qry, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, err
+	}
+	ids, err := selectIDs(ctx, h.files, cursor)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, id := range ids {
+		if err := h.bucket.DeleteContext(ctx, id); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, ctx.Err()
+}
+
+// Find lists files matching q. The "content" field is only populated with an
+// io.Reader (via DownloadToStream) when explicitly requested in q.Projection;
+// otherwise only metadata is returned.
+func (h *gridFSHandler) Find(ctx context.Context, q *query.Query) (*resource.ItemList, error) {
+	qry, err := getQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	findOptions := options.Find().SetSort(getSort(q))
+	if q.Window != nil {
+		findOptions = applyWindow(findOptions, *q.Window)
+	}
+	cursor, err := h.files.Find(ctx, qry, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	list := &resource.ItemList{Total: -1, Limit: -1, Items: []*resource.Item{}}
+	if q.Window != nil {
+		list.Limit = q.Window.Limit
+	}
+	for cursor.Next(ctx) {
+		var f struct {
+			ID       interface{} `bson:"_id"`
+			Metadata bson.M      `bson:"metadata"`
+		}
+		if err := cursor.Decode(&f); err != nil {
+			return nil, err
+		}
+		etag, _ := f.Metadata["etag"].(string)
+		updated, _ := f.Metadata["updated"].(time.Time)
+		item := newItemFromGridFSFile(f.ID, etag, updated, f.Metadata)
+		if wantsContent(q) {
+			var buf bytes.Buffer
+			if _, err := h.bucket.DownloadToStream(f.ID, &buf); err != nil {
+				return nil, err
+			}
+			item.Payload[gridFSContentField] = io.Reader(bytes.NewReader(buf.Bytes()))
+		}
+		list.Items = append(list.Items, item)
+	}
+	if err := cursor.Close(ctx); err != nil {
+		return nil, err
+	}
+	if list.Limit < 0 || len(list.Items) < list.Limit {
+		list.Total = len(list.Items)
+	}
+	return list, nil
+}
+
+func wantsContent(q *query.Query) bool {
+	if len(q.Projection) == 0 {
+		return false
+	}
+	for _, f := range q.Projection {
+		if f.Name == "*" || f.Name == gridFSContentField {
+			return true
+		}
+	}
+	return false
+}
+
+func filename(item *resource.Item) string {
+	if name, ok := item.Payload["filename"].(string); ok && name != "" {
+		return name
+	}
+	return strings.TrimSpace(fmt.Sprint(item.ID))
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/rs/rest-layer/resource"
@@ -68,16 +67,47 @@ func newItem(i *mongoItem) *resource.Item {
 }
 
 // Handler handles resource storage in a MongoDB collection.
-type Handler func(ctx context.Context) (*mongo.Collection, error)
+type Handler struct {
+	collFn func(ctx context.Context) (*mongo.Collection, error)
+	// atlasSearch enables AggregateFullText's Atlas Search $search stage.
+	// FullText predicates in a query still translate to the $text operator.
+	atlasSearch bool
+	// bulkBatchSize overrides defaultBulkBatchSize for InsertMany, UpdateMany
+	// and DeleteMany when set via WithBulkBatchSize.
+	bulkBatchSize int
+	// exactTotals makes Find compute its total via a $facet aggregation
+	// instead of deducing it, when set via WithExactTotals.
+	exactTotals bool
+}
+
+// HandlerOption configures optional Handler behavior at construction time.
+type HandlerOption func(*Handler)
+
+// WithAtlasSearch enables AggregateFullText, which runs a full-text search
+// as a MongoDB Atlas Search $search aggregation stage (a compound must or
+// mustNot clause with fuzzy matching). FullText predicates passed to Find
+// and the other query methods are translated to the self-hosted $text
+// operator whether or not this is set.
+func WithAtlasSearch(enabled bool) HandlerOption {
+	return func(h *Handler) {
+		h.atlasSearch = enabled
+	}
+}
 
 // NewHandler creates an new mongo handler
-func NewHandler(s *mongo.Client, db, collection string) Handler {
+func NewHandler(s *mongo.Client, db, collection string, opts ...HandlerOption) Handler {
 	c := func() *mongo.Collection {
 		return s.Database(db).Collection(collection)
 	}
-	return func(ctx context.Context) (*mongo.Collection, error) {
-		return c(), nil
+	h := Handler{
+		collFn: func(ctx context.Context) (*mongo.Collection, error) {
+			return c(), nil
+		},
+	}
+	for _, opt := range opts {
+		opt(&h)
 	}
+	return h
 }
 
 // C returns the mongo collection managed by this storage handler
@@ -86,7 +116,7 @@ func (m Handler) c(ctx context.Context) (*mongo.Collection, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-	c, err := m(ctx)
+	c, err := m.collFn(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -139,22 +169,21 @@ func isDup(err error) bool {
 
 // Insert inserts new items in the mongo collection.
 func (m Handler) Insert(ctx context.Context, items []*resource.Item) error {
-	mItems := make([]interface{}, len(items))
-	for i, item := range items {
-		mItems[i] = newMongoItem(item)
-	}
-	c, err := m.c(ctx)
+	res, err := m.InsertMany(ctx, items)
 	if err != nil {
 		return err
 	}
-	defer m.close(c)
-	_, err = c.InsertMany(ctx, mItems)
-	if isDup(err) {
-		// Duplicate ID key
-		err = resource.ErrConflict
-	}
-	if ctx.Err() != nil {
-		return ctx.Err()
+	first := -1
+	for i, itemErr := range res.Errors {
+		// Insert is all-or-nothing from the caller's point of view: report
+		// the first per-item failure, conflicts taking priority since
+		// that's what single-document Insert historically returned.
+		if errors.Is(itemErr, resource.ErrConflict) {
+			return resource.ErrConflict
+		}
+		if first < 0 || i < first {
+			first, err = i, itemErr
+		}
 	}
 	return err
 }
@@ -167,15 +196,7 @@ func (m Handler) Update(ctx context.Context, item *resource.Item, original *reso
 		return err
 	}
 	defer m.close(c)
-	s := bson.M{"_id": original.ID}
-	if strings.HasPrefix(original.ETag, "p-") {
-		// If the original ETag is in "p-[id]" format,
-		// then _etag field must be absent from the resource in DB
-		s["_etag"] = bson.M{"$exists": false}
-	} else {
-		s["_etag"] = original.ETag
-	}
-	info, err := c.ReplaceOne(ctx, s, mItem)
+	info, err := c.ReplaceOne(ctx, etagFilter(original), mItem)
 	if err != nil {
 		return err
 	}
@@ -203,15 +224,7 @@ func (m Handler) Delete(ctx context.Context, item *resource.Item) error {
 		return err
 	}
 	defer m.close(c)
-	s := bson.M{"_id": item.ID}
-	if strings.HasPrefix(item.ETag, "p-") {
-		// If the item ETag is in "p-[id]" format,
-		// then _etag field must be absent from the resource in DB
-		s["_etag"] = bson.M{"$exists": false}
-	} else {
-		s["_etag"] = item.ETag
-	}
-	info, err := c.DeleteOne(ctx, s)
+	info, err := c.DeleteOne(ctx, etagFilter(item))
 	if err != nil {
 		return err
 	}
@@ -303,6 +316,10 @@ func (m Handler) Find(ctx context.Context, q *query.Query) (*resource.ItemList,
 		return list, err
 	}
 
+	if m.exactTotals {
+		return m.findWithExactTotals(ctx, q)
+	}
+
 	qry, err := getQuery(q)
 	if err != nil {
 		return nil, err
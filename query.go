@@ -79,11 +79,44 @@ func getProjection(q *query.Query) bson.M {
 		if len(fname) > 1 {
 			name = fname[0]
 		}
+		if name == "score" && hasFullTextPredicate(q.Predicate) {
+			// Project the $text relevance score so it can be used in a sort.
+			// Only valid alongside an actual $text/$search stage, so a plain
+			// field named "score" isn't hijacked and {$meta: "textScore"}
+			// isn't emitted when there's no text-score metadata to read.
+			p[name] = textScoreProjection()["score"]
+			continue
+		}
 		p[getField(name)] = 1
 	}
 	return p
 }
 
+// hasFullTextPredicate reports whether p includes a FullText expression,
+// possibly nested under $and/$or, so getProjection knows a $text/$search
+// stage will actually be present to back a {$meta: "textScore"} projection.
+func hasFullTextPredicate(p query.Predicate) bool {
+	for _, exp := range p {
+		switch t := exp.(type) {
+		case *FullText:
+			return true
+		case *query.And:
+			for _, sub := range *t {
+				if hasFullTextPredicate(expToPredicate(sub)) {
+					return true
+				}
+			}
+		case *query.Or:
+			for _, sub := range *t {
+				if hasFullTextPredicate(expToPredicate(sub)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 func hasStarProjection(q *query.Query) bool {
 	for _, field := range q.Projection {
 		if field.Name == "*" {
@@ -177,6 +210,10 @@ func translatePredicate(q query.Predicate) (bson.M, error) {
 			b[getField(t.Field)] = bson.M{"$lt": t.Value}
 		case *query.LowerOrEqual:
 			b[getField(t.Field)] = bson.M{"$lte": t.Value}
+		case *FullText:
+			for k, v := range textSearchStage(t) {
+				b[k] = v
+			}
 		case *query.Regex:
 			if t.Negated {
 				b[getField(t.Field)] = bson.M{"$not": primitive.Regex{Pattern: t.Value.String()}}
@@ -190,6 +227,28 @@ func translatePredicate(q query.Predicate) (bson.M, error) {
 	return b, nil
 }
 
+// prefixFields rewrites every leaf field key produced by translatePredicate
+// so it's rooted at prefix (e.g. "fullDocument."), recursing through $and/
+// $or so a compound predicate doesn't get its operator keys mistaken for
+// field names (prefixing "$and" itself would produce an invalid path).
+func prefixFields(b bson.M, prefix string) bson.M {
+	out := bson.M{}
+	for k, v := range b {
+		switch k {
+		case "$and", "$or":
+			subs, _ := v.([]bson.M)
+			prefixed := make([]bson.M, len(subs))
+			for i, sub := range subs {
+				prefixed[i] = prefixFields(sub, prefix)
+			}
+			out[k] = prefixed
+		default:
+			out[prefix+k] = v
+		}
+	}
+	return out
+}
+
 func expToPredicate(exp query.Expression) query.Predicate {
 	switch t := exp.(type) {
 	case query.Predicate:
@@ -0,0 +1,41 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TxHandler marks a Handler for use inside a WithSession transaction. Insert,
+// Update, Delete and Clear already honor any mongo.Session carried by ctx, so
+// wrapping a Handler in a TxHandler does not change their behavior: it only
+// documents, at bind time, that callers are expected to invoke it from
+// within the fn passed to WithSession.
+type TxHandler struct {
+	Handler
+}
+
+// NewTxHandler wraps h so it can be bound alongside other resources that
+// must commit together in the same transaction.
+func NewTxHandler(h Handler) TxHandler {
+	return TxHandler{Handler: h}
+}
+
+// WithSession starts a session on client and runs fn inside a multi-document
+// transaction via session.WithTransaction, which retries the whole
+// transaction on TransientTransactionError and the commit alone on
+// UnknownTransactionCommitResult, as recommended by the driver, bounded by
+// its own 120s retry timeout. This lets a REST request that touches several
+// bound resources (e.g. a user and its initial posts) commit atomically.
+func WithSession(ctx context.Context, client *mongo.Client, fn func(ctx context.Context) error) error {
+	sess, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+
+	_, err = sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
@@ -0,0 +1,138 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/rs/rest-layer/resource"
+	"github.com/rs/rest-layer/schema/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ResumeTokenStore persists change stream resume tokens so a Watcher can pick
+// up where it left off across reconnects. id identifies the stream (e.g. the
+// collection name) so a single store can be shared across several Watchers.
+type ResumeTokenStore interface {
+	GetResumeToken(ctx context.Context, id string) (bson.Raw, error)
+	SetResumeToken(ctx context.Context, id string, token bson.Raw) error
+}
+
+// ChangeEvent is a single change stream event translated into a resource.Item.
+type ChangeEvent struct {
+	// OperationType is one of "insert", "update", "delete" or "replace".
+	OperationType string
+	// Item is the affected item's current state. It is nil for delete
+	// events, where only DocumentID is available.
+	Item *resource.Item
+	// DocumentID is the _id of the affected document.
+	DocumentID interface{}
+}
+
+// Watcher streams change events from a Handler's collection, optionally
+// persisting its resume token via a ResumeTokenStore so that watching can
+// resume after a disconnect instead of replaying the whole collection.
+type Watcher struct {
+	h     Handler
+	store ResumeTokenStore
+	id    string
+}
+
+// NewWatcher creates a Watcher over the collection served by h. store may be
+// nil, in which case the stream always starts from "now" on (re)connect.
+func NewWatcher(h Handler, store ResumeTokenStore, id string) *Watcher {
+	return &Watcher{h: h, store: store, id: id}
+}
+
+// changeStreamDoc mirrors the subset of a change stream event document that
+// Watch needs to decode.
+type changeStreamDoc struct {
+	OperationType string    `bson:"operationType"`
+	FullDocument  mongoItem `bson:"fullDocument"`
+	DocumentKey   struct {
+		ID interface{} `bson:"_id"`
+	} `bson:"documentKey"`
+}
+
+// Watch opens a change stream filtered by q, if any, and returns a channel of
+// ChangeEvents. The returned channel is closed when ctx is canceled or the
+// stream errors; callers should check ctx.Err() in that case. Matching
+// documents are decoded with the same _id/_etag/_updated handling as Find.
+func (w *Watcher) Watch(ctx context.Context, q *query.Query) (<-chan ChangeEvent, error) {
+	c, err := w.h.c(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{
+			"operationType": bson.M{"$in": []string{"insert", "update", "delete", "replace"}},
+		}}},
+	}
+	if q != nil {
+		qry, err := translatePredicate(q.Predicate)
+		if err != nil {
+			return nil, err
+		}
+		// Rewrite field keys to be rooted at fullDocument rather than
+		// naively prefixing every top-level key: a compound predicate
+		// ($and/$or) produces operator keys, not field names, and
+		// "fullDocument.$and" would be an invalid $match path.
+		match := prefixFields(qry, "fullDocument.")
+		if len(match) > 0 {
+			// Delete events carry no fullDocument, so they can never satisfy
+			// a fullDocument.<field> match; let them through unfiltered
+			// rather than silently dropping every delete once q has a
+			// predicate.
+			pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{
+				"$or": []bson.M{
+					{"operationType": "delete"},
+					match,
+				},
+			}}})
+		}
+	}
+
+	streamOptions := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if w.store != nil {
+		if token, err := w.store.GetResumeToken(ctx, w.id); err == nil && token != nil {
+			streamOptions.SetResumeAfter(token)
+		}
+	}
+
+	stream, err := c.Watch(ctx, pipeline, streamOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan ChangeEvent)
+	go func() {
+		defer close(events)
+		defer stream.Close(ctx)
+		for stream.Next(ctx) {
+			var doc changeStreamDoc
+			if err := stream.Decode(&doc); err != nil {
+				return
+			}
+			if w.store != nil {
+				if err := w.store.SetResumeToken(ctx, w.id, stream.ResumeToken()); err != nil {
+					return
+				}
+			}
+			event := ChangeEvent{
+				OperationType: doc.OperationType,
+				DocumentID:    doc.DocumentKey.ID,
+			}
+			if doc.OperationType != "delete" {
+				event.Item = newItem(&doc.FullDocument)
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}